@@ -0,0 +1,177 @@
+package taxis99
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Authenticator attaches credentials to an outgoing request before it is
+// sent to the 99Taxis API.
+type Authenticator interface {
+	Authorize(ctx context.Context, req *http.Request) error
+}
+
+// Invalidator is implemented by Authenticators that cache a credential and
+// can discard it, e.g. after the API responds with 401 Unauthorized.
+type Invalidator interface {
+	Invalidate()
+}
+
+// oauth2Token is a cached access token along with the time it expires at.
+type oauth2Token struct {
+	AccessToken string
+	ExpiresAt   time.Time
+}
+
+func (t *oauth2Token) valid() bool {
+	return t != nil && time.Now().Before(t.ExpiresAt)
+}
+
+// OAuth2Authenticator authenticates requests against the 99Taxis API using
+// the OAuth2 client-credentials grant. It caches the access token in memory
+// and transparently fetches a new one when missing or expired, coalescing
+// concurrent refreshes into a single token request.
+type OAuth2Authenticator struct {
+	// TokenURL is the OAuth2 token endpoint.
+	TokenURL string
+
+	// ClientID and ClientSecret are exchanged for an access token.
+	ClientID     string
+	ClientSecret string
+
+	// HTTPClient is used to call TokenURL. If nil, http.DefaultClient is
+	// used.
+	HTTPClient *http.Client
+
+	mu       sync.Mutex
+	token    *oauth2Token
+	inFlight *tokenCall
+}
+
+// tokenCall represents a token refresh shared by every caller that observed
+// it in flight.
+type tokenCall struct {
+	done  chan struct{}
+	token *oauth2Token
+	err   error
+}
+
+// NewOAuth2Authenticator returns an Authenticator that exchanges clientID
+// and clientSecret for bearer tokens at tokenURL. If hc is nil,
+// http.DefaultClient is used.
+func NewOAuth2Authenticator(hc *http.Client, tokenURL, clientID, clientSecret string) *OAuth2Authenticator {
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+
+	return &OAuth2Authenticator{
+		TokenURL:     tokenURL,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		HTTPClient:   hc,
+	}
+}
+
+// Authorize sets the Authorization header on req, reusing the cached access
+// token or fetching a fresh one if it is missing or expired.
+func (a *OAuth2Authenticator) Authorize(ctx context.Context, req *http.Request) error {
+	tok, err := a.getToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+tok.AccessToken)
+	return nil
+}
+
+// Invalidate discards the cached access token, forcing the next Authorize
+// call to fetch a fresh one.
+func (a *OAuth2Authenticator) Invalidate() {
+	a.mu.Lock()
+	a.token = nil
+	a.mu.Unlock()
+}
+
+// getToken returns the cached token if still valid, otherwise fetches a new
+// one. Concurrent callers that arrive while a refresh is in flight wait for
+// and share its result instead of issuing their own request.
+func (a *OAuth2Authenticator) getToken(ctx context.Context) (*oauth2Token, error) {
+	a.mu.Lock()
+	if a.token.valid() {
+		tok := a.token
+		a.mu.Unlock()
+		return tok, nil
+	}
+
+	if call := a.inFlight; call != nil {
+		a.mu.Unlock()
+		<-call.done
+		return call.token, call.err
+	}
+
+	call := &tokenCall{done: make(chan struct{})}
+	a.inFlight = call
+	a.mu.Unlock()
+
+	tok, err := a.fetchToken(ctx)
+
+	a.mu.Lock()
+	call.token, call.err = tok, err
+	if err == nil {
+		a.token = tok
+	}
+	a.inFlight = nil
+	a.mu.Unlock()
+
+	close(call.done)
+	return tok, err
+}
+
+// fetchToken performs the client-credentials exchange against TokenURL.
+func (a *OAuth2Authenticator) fetchToken(ctx context.Context) (*oauth2Token, error) {
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {a.ClientID},
+		"client_secret": {a.ClientSecret},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, &APIError{Op: "oauth2: build token request", Err: err}
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	hc := a.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+
+	res, err := hc.Do(req)
+	if err != nil {
+		return nil, &APIError{Op: "oauth2: token request", Err: err}
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &APIError{Op: "oauth2: token request", Err: fmt.Errorf("unexpected status %d", res.StatusCode)}
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return nil, &APIError{Op: "oauth2: decode token response", Err: err}
+	}
+
+	return &oauth2Token{
+		AccessToken: body.AccessToken,
+		ExpiresAt:   time.Now().Add(time.Duration(body.ExpiresIn) * time.Second),
+	}, nil
+}
@@ -0,0 +1,149 @@
+package taxis99
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+)
+
+func tokenServer(t *testing.T) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token-%d","expires_in":3600}`, n)
+	}))
+
+	return srv, &calls
+}
+
+func TestOAuth2AuthenticatorAuthorize(t *testing.T) {
+	srv, calls := tokenServer(t)
+	defer srv.Close()
+
+	auth := NewOAuth2Authenticator(nil, srv.URL, "client-id", "client-secret")
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	if err := auth.Authorize(context.Background(), req); err != nil {
+		t.Fatalf("Got error calling Authorize: %s; want it to be nil.", err.Error())
+	}
+
+	if got, want := req.Header.Get("Authorization"), "Bearer token-1"; got != want {
+		t.Errorf("Got Authorization header %q; want %q.", got, want)
+	}
+
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("Got %d token requests; want 1.", got)
+	}
+}
+
+func TestOAuth2AuthenticatorCachesToken(t *testing.T) {
+	srv, calls := tokenServer(t)
+	defer srv.Close()
+
+	auth := NewOAuth2Authenticator(nil, srv.URL, "client-id", "client-secret")
+
+	for i := 0; i < 3; i++ {
+		req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+		if err := auth.Authorize(context.Background(), req); err != nil {
+			t.Fatalf("Got error calling Authorize: %s; want it to be nil.", err.Error())
+		}
+	}
+
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("Got %d token requests; want 1 (cached).", got)
+	}
+}
+
+func TestOAuth2AuthenticatorInvalidate(t *testing.T) {
+	srv, calls := tokenServer(t)
+	defer srv.Close()
+
+	auth := NewOAuth2Authenticator(nil, srv.URL, "client-id", "client-secret")
+
+	req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	if err := auth.Authorize(context.Background(), req); err != nil {
+		t.Fatalf("Got error calling Authorize: %s; want it to be nil.", err.Error())
+	}
+
+	auth.Invalidate()
+
+	req2, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	if err := auth.Authorize(context.Background(), req2); err != nil {
+		t.Fatalf("Got error calling Authorize: %s; want it to be nil.", err.Error())
+	}
+
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Errorf("Got %d token requests; want 2 (refreshed after Invalidate).", got)
+	}
+}
+
+func TestOAuth2AuthenticatorConcurrentRefreshSingleflight(t *testing.T) {
+	srv, calls := tokenServer(t)
+	defer srv.Close()
+
+	auth := NewOAuth2Authenticator(nil, srv.URL, "client-id", "client-secret")
+
+	const n = 10
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		go func() {
+			req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+			errs <- auth.Authorize(context.Background(), req)
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("Got error calling Authorize: %s; want it to be nil.", err.Error())
+		}
+	}
+
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("Got %d token requests; want 1 (single refresh shared by all callers).", got)
+	}
+}
+
+func TestClientRequestAuthorizesAndRetriesOn401(t *testing.T) {
+	srv, calls := tokenServer(t)
+	defer srv.Close()
+
+	auth := NewOAuth2Authenticator(nil, srv.URL, "client-id", "client-secret")
+
+	var apiCalls int32
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&apiCalls, 1)
+		if n == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if got, want := r.Header.Get("Authorization"), "Bearer token-2"; got != want {
+			t.Errorf("Got Authorization header %q on retry; want %q.", got, want)
+		}
+	}))
+	defer api.Close()
+
+	client := NewClientWithAuth(nil, auth)
+	u, _ := url.Parse(api.URL + "/")
+	client.BaseURL = u
+
+	err := client.Request(context.Background(), http.MethodGet, "", nil, nil)
+	if err != nil {
+		t.Fatalf("Got error calling Request: %s; want it to be nil.", err.Error())
+	}
+
+	if got := atomic.LoadInt32(&apiCalls); got != 2 {
+		t.Errorf("Got %d API calls; want 2 (original + retry after 401).", got)
+	}
+
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Errorf("Got %d token requests; want 2 (cached token invalidated after 401, refetched for retry).", got)
+	}
+}
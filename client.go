@@ -0,0 +1,204 @@
+// Package taxis99 provides a client for the 99Taxis corporate API.
+package taxis99
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Client is a thin HTTP client for the 99Taxis corporate API. It takes care
+// of building requests, marshaling JSON bodies, decoding JSON responses and,
+// when configured, authenticating the request.
+type Client struct {
+	// HTTPClient is the underlying HTTP client used to perform requests. If
+	// nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+
+	// BaseURL is prepended to every path passed to Request.
+	BaseURL *url.URL
+
+	// Auth, when set, authorizes every outgoing request. If Auth is also an
+	// Invalidator, a response with status 401 causes the cached credential
+	// to be invalidated and the request to be retried once; otherwise a 401
+	// is not retried.
+	Auth Authenticator
+
+	// Retry controls how a retryable response or transport error is retried.
+	// The zero value disables retries.
+	Retry RetryPolicy
+
+	// RateLimiter, when set, is waited on before each attempt is sent.
+	RateLimiter RateLimiter
+
+	// Debug, when set, is invoked with a DebugEvent after each round trip
+	// that receives a response (transport errors are not reported).
+	Debug func(DebugEvent)
+}
+
+// NewClient returns a Client that talks to the 99Taxis API without any
+// authentication.
+func NewClient(hc *http.Client) *Client {
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+
+	return &Client{
+		HTTPClient: hc,
+		BaseURL:    &url.URL{},
+	}
+}
+
+// NewClientWithAuth returns a Client that authorizes every request using
+// auth before sending it.
+func NewClientWithAuth(hc *http.Client, auth Authenticator) *Client {
+	c := NewClient(hc)
+	c.Auth = auth
+	return c
+}
+
+// Request performs an HTTP call against path, optionally marshaling body as
+// the JSON request payload and unmarshaling the JSON response into out.
+// Both body and out may be nil. If a 401 is received and Auth is an
+// Invalidator, the cached credential is invalidated and the request is
+// retried once. A response or transport error matching c.Retry is retried
+// with exponential backoff, for at most c.Retry.MaxAttempts attempts in
+// total.
+func (c *Client) Request(ctx context.Context, method, path string, body, out interface{}) error {
+	var bodyBytes []byte
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return &APIError{Op: "marshal", Err: err}
+		}
+		bodyBytes = b
+	}
+
+	maxAttempts := c.Retry.maxAttempts()
+
+	var res *http.Response
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		res, err = c.attempt(ctx, method, path, bodyBytes, body != nil)
+
+		var wait time.Duration
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return err
+			}
+		case c.Retry.retryableStatus(res.StatusCode):
+			wait = retryAfter(res)
+		default:
+			wait = -1 // not retryable
+		}
+
+		if wait < 0 || attempt == maxAttempts-1 {
+			break
+		}
+
+		if res != nil {
+			res.Body.Close()
+		}
+
+		if wait == 0 {
+			wait = c.Retry.backoff(attempt)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 0 && (res.StatusCode < 200 || res.StatusCode >= 300) {
+		return parseAPIError(res)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return &APIError{Op: "decode", Err: err}
+	}
+
+	return nil
+}
+
+// attempt performs a single try of a request, including the one-shot
+// reauthorize-and-retry on a 401 response.
+func (c *Client) attempt(ctx context.Context, method, path string, bodyBytes []byte, hasBody bool) (*http.Response, error) {
+	res, err := c.do(ctx, method, path, bodyBytes, hasBody)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == http.StatusUnauthorized {
+		if inv, ok := c.Auth.(Invalidator); ok {
+			inv.Invalidate()
+			res.Body.Close()
+
+			res, err = c.do(ctx, method, path, bodyBytes, hasBody)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// do builds and sends a single HTTP round trip, authorizing it with c.Auth
+// when set.
+func (c *Client) do(ctx context.Context, method, path string, bodyBytes []byte, hasBody bool) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL.String()+path, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	if hasBody {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if c.Auth != nil {
+		if err := c.Auth.Authorize(ctx, req); err != nil {
+			return nil, &APIError{Op: "authorize", Err: err}
+		}
+	}
+
+	if c.RateLimiter != nil {
+		if err := c.RateLimiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+
+	start := time.Now()
+	res, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.debug(req, bodyBytes, res, time.Since(start)); err != nil {
+		res.Body.Close()
+		return nil, err
+	}
+
+	return res, nil
+}
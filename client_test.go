@@ -218,6 +218,8 @@ func (fn testRoundTripperFn) RoundTrip(r *http.Request) (*http.Response, error)
 	return fn(r)
 }
 
+type testRoundTripper = testRoundTripperFn
+
 func TestClientRequestEmptyOutput(t *testing.T) {
 	response := []byte(`{"name":"test"}`)
 
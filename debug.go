@@ -0,0 +1,81 @@
+package taxis99
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// DebugEvent describes a single HTTP round trip performed by Client.Request,
+// for use by Client.Debug.
+type DebugEvent struct {
+	// Method and URL identify the request.
+	Method string
+	URL    string
+
+	// RequestHeader is a copy of the request headers with Authorization
+	// redacted.
+	RequestHeader http.Header
+
+	// RequestBody is the raw request body, or nil if the request had none.
+	RequestBody []byte
+
+	// StatusCode is the response status code.
+	StatusCode int
+
+	// ResponseHeader is a copy of the response headers.
+	ResponseHeader http.Header
+
+	// ResponseBody is the raw response body.
+	ResponseBody []byte
+
+	// Duration is the time spent waiting for the response.
+	Duration time.Duration
+}
+
+// redactHeader returns a copy of h with the Authorization header removed.
+func redactHeader(h http.Header) http.Header {
+	redacted := h.Clone()
+	redacted.Del("Authorization")
+	return redacted
+}
+
+// debug, if c.Debug is set, reads and restores res.Body, then invokes
+// c.Debug with a DebugEvent describing the round trip. It is only called
+// once a response has been received, never on a transport error.
+func (c *Client) debug(req *http.Request, reqBody []byte, res *http.Response, elapsed time.Duration) error {
+	if c.Debug == nil {
+		return nil
+	}
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	c.Debug(DebugEvent{
+		Method:         req.Method,
+		URL:            req.URL.String(),
+		RequestHeader:  redactHeader(req.Header),
+		RequestBody:    reqBody,
+		StatusCode:     res.StatusCode,
+		ResponseHeader: res.Header.Clone(),
+		ResponseBody:   resBody,
+		Duration:       elapsed,
+	})
+
+	return nil
+}
+
+// NewLoggingDebugger returns a Client.Debug hook that writes each
+// DebugEvent to w as a single-line structured log.
+func NewLoggingDebugger(w io.Writer) func(DebugEvent) {
+	return func(e DebugEvent) {
+		fmt.Fprintf(w, "taxis99: method=%s url=%s status=%d duration=%s request_bytes=%d response_bytes=%d\n",
+			e.Method, e.URL, e.StatusCode, e.Duration, len(e.RequestBody), len(e.ResponseBody))
+	}
+}
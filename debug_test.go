@@ -0,0 +1,54 @@
+package taxis99
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestClientRequestDebugRedactsAuthorization(t *testing.T) {
+	c, srv := newMockServer(nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok":true}`))
+	})
+	defer srv.Close()
+
+	c.Auth = staticAuthenticator("secret-token")
+
+	var event DebugEvent
+	var called bool
+	c.Debug = func(e DebugEvent) {
+		called = true
+		event = e
+	}
+
+	var out struct {
+		OK bool `json:"ok"`
+	}
+	if err := c.Request(context.Background(), http.MethodGet, "", nil, &out); err != nil {
+		t.Fatalf("Got error %v; want nil.", err)
+	}
+
+	if !called {
+		t.Fatal("Got Debug not called; want it to be called.")
+	}
+	if event.StatusCode != http.StatusOK {
+		t.Errorf("Got StatusCode %d; want %d.", event.StatusCode, http.StatusOK)
+	}
+	if got := event.RequestHeader.Get("Authorization"); got != "" {
+		t.Errorf("Got Authorization %q; want redacted.", got)
+	}
+	if string(event.ResponseBody) != `{"ok":true}` {
+		t.Errorf("Got ResponseBody %q; want %q.", event.ResponseBody, `{"ok":true}`)
+	}
+	if !out.OK {
+		t.Error("Got out.OK false; want true, since Debug should not consume the decode body.")
+	}
+}
+
+// staticAuthenticator sets a fixed Authorization header, for tests.
+type staticAuthenticator string
+
+func (a staticAuthenticator) Authorize(ctx context.Context, req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+string(a))
+	return nil
+}
@@ -0,0 +1,105 @@
+package taxis99
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// APIError describes a failure encountered while performing a request. It
+// is returned either when marshaling the body, authorizing the request
+// (including fetching an OAuth2 token) or decoding the response fails (Op
+// describes the failing step and Err wraps the underlying error), or when
+// the 99Taxis API responded with a non-2xx status (StatusCode, Code,
+// Message, RequestID and Body are populated from the response). Errors from
+// building the request, from the RateLimiter and from the transport are
+// returned as they are, not wrapped in an APIError.
+type APIError struct {
+	// Op names the step that failed, e.g. "marshal", "decode" or
+	// "authorize". Empty when StatusCode is set.
+	Op  string
+	Err error
+
+	// StatusCode is the HTTP status code of a non-2xx response.
+	StatusCode int
+
+	// Code and Message come from the 99Taxis error envelope
+	// ({"error":{"code":"...","message":"..."}}), when the response body is
+	// JSON in that shape.
+	Code    string
+	Message string
+
+	// RequestID is copied from the X-Request-Id response header, if present.
+	RequestID string
+
+	// Body is the raw response body.
+	Body []byte
+}
+
+func (e *APIError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("taxis99: status %d: %s: %s", e.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Sprintf("taxis99: %s: %s", e.Op, e.Err)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// errorEnvelope is the 99Taxis error response shape.
+type errorEnvelope struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// parseAPIError builds an APIError from a non-2xx response, decoding the
+// 99Taxis error envelope when the body is JSON in that shape and otherwise
+// falling back to the raw body.
+func parseAPIError(res *http.Response) *APIError {
+	apiErr := &APIError{
+		StatusCode: res.StatusCode,
+		RequestID:  res.Header.Get("X-Request-Id"),
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		apiErr.Err = err
+		return apiErr
+	}
+	apiErr.Body = body
+
+	var envelope errorEnvelope
+	if err := json.Unmarshal(body, &envelope); err == nil && envelope.Error.Code != "" {
+		apiErr.Code = envelope.Error.Code
+		apiErr.Message = envelope.Error.Message
+	} else {
+		apiErr.Message = string(body)
+	}
+
+	return apiErr
+}
+
+// IsNotFound reports whether err is an APIError with StatusCode 404.
+func IsNotFound(err error) bool {
+	return hasStatusCode(err, http.StatusNotFound)
+}
+
+// IsUnauthorized reports whether err is an APIError with StatusCode 401.
+func IsUnauthorized(err error) bool {
+	return hasStatusCode(err, http.StatusUnauthorized)
+}
+
+// IsRateLimited reports whether err is an APIError with StatusCode 429.
+func IsRateLimited(err error) bool {
+	return hasStatusCode(err, http.StatusTooManyRequests)
+}
+
+func hasStatusCode(err error, code int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == code
+}
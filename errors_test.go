@@ -0,0 +1,102 @@
+package taxis99
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func newErrorMockServer(t *testing.T, status int, requestID string, body string) (*Client, *httptest.Server) {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requestID != "" {
+			w.Header().Set("X-Request-Id", requestID)
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+
+	c := NewClient(nil)
+	u, _ := url.Parse(srv.URL + "/")
+	c.BaseURL = u
+
+	return c, srv
+}
+
+func TestClientRequestAPIErrorEnvelope(t *testing.T) {
+	c, srv := newErrorMockServer(t, http.StatusNotFound, "req-123", `{"error":{"code":"ride_not_found","message":"ride does not exist"}}`)
+	defer srv.Close()
+
+	err := c.Request(context.Background(), http.MethodGet, "", nil, nil)
+	if err == nil {
+		t.Fatal("Got error nil; want it not to be nil.")
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Got error %T; want *APIError.", err)
+	}
+
+	if apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Got StatusCode %d; want %d.", apiErr.StatusCode, http.StatusNotFound)
+	}
+	if apiErr.Code != "ride_not_found" {
+		t.Errorf("Got Code %q; want %q.", apiErr.Code, "ride_not_found")
+	}
+	if apiErr.Message != "ride does not exist" {
+		t.Errorf("Got Message %q; want %q.", apiErr.Message, "ride does not exist")
+	}
+	if apiErr.RequestID != "req-123" {
+		t.Errorf("Got RequestID %q; want %q.", apiErr.RequestID, "req-123")
+	}
+
+	if !IsNotFound(err) {
+		t.Error("Got IsNotFound false; want true.")
+	}
+	if IsUnauthorized(err) || IsRateLimited(err) {
+		t.Error("Got IsUnauthorized or IsRateLimited true; want both false.")
+	}
+}
+
+func TestClientRequestAPIErrorNonJSONBody(t *testing.T) {
+	c, srv := newErrorMockServer(t, http.StatusInternalServerError, "", "internal server error")
+	defer srv.Close()
+
+	err := c.Request(context.Background(), http.MethodGet, "", nil, nil)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Got error %T; want *APIError.", err)
+	}
+
+	if apiErr.Code != "" {
+		t.Errorf("Got Code %q; want empty for a non-JSON body.", apiErr.Code)
+	}
+	if string(apiErr.Body) != "internal server error" {
+		t.Errorf("Got Body %q; want %q.", apiErr.Body, "internal server error")
+	}
+}
+
+func TestIsUnauthorized(t *testing.T) {
+	c, srv := newErrorMockServer(t, http.StatusUnauthorized, "", `{"error":{"code":"invalid_token","message":"token expired"}}`)
+	defer srv.Close()
+
+	err := c.Request(context.Background(), http.MethodGet, "", nil, nil)
+	if !IsUnauthorized(err) {
+		t.Error("Got IsUnauthorized false; want true.")
+	}
+}
+
+func TestIsRateLimited(t *testing.T) {
+	c, srv := newErrorMockServer(t, http.StatusTooManyRequests, "", `{"error":{"code":"rate_limited","message":"too many requests"}}`)
+	defer srv.Close()
+
+	err := c.Request(context.Background(), http.MethodGet, "", nil, nil)
+	if !IsRateLimited(err) {
+		t.Error("Got IsRateLimited false; want true.")
+	}
+}
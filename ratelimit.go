@@ -0,0 +1,90 @@
+package taxis99
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter is consulted by Client.Request just before a request is sent,
+// to throttle the rate of outgoing requests.
+type RateLimiter interface {
+	// Wait blocks until a request may proceed or ctx is done, in which case
+	// it returns ctx.Err().
+	Wait(ctx context.Context) error
+}
+
+// NoopLimiter is a RateLimiter that never blocks. It is useful in tests.
+type NoopLimiter struct{}
+
+// Wait always returns nil immediately.
+func (NoopLimiter) Wait(ctx context.Context) error {
+	return nil
+}
+
+// tokenBucketLimiter is a RateLimiter backed by a token bucket: it holds up
+// to burst tokens and refills at qps tokens per second, similar to
+// k8s.io/client-go/util/flowcontrol.
+type tokenBucketLimiter struct {
+	qps   float64
+	burst int
+
+	mu       sync.Mutex
+	tokens   float64
+	lastFill time.Time
+}
+
+// NewTokenBucketLimiter returns a RateLimiter allowing qps requests per
+// second on average, with bursts of up to burst requests.
+func NewTokenBucketLimiter(qps float64, burst int) RateLimiter {
+	return &tokenBucketLimiter{
+		qps:      qps,
+		burst:    burst,
+		tokens:   float64(burst),
+		lastFill: time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or ctx is done. A canceled ctx
+// does not consume a token.
+func (l *tokenBucketLimiter) Wait(ctx context.Context) error {
+	for {
+		wait, ok := l.take()
+		if ok {
+			return nil
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// take refills the bucket for elapsed time and, if a token is available,
+// consumes one and returns (0, true). Otherwise it returns the duration
+// until the next token would be available and false.
+func (l *tokenBucketLimiter) take() (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	elapsed := now.Sub(l.lastFill)
+	l.lastFill = now
+
+	l.tokens += elapsed.Seconds() * l.qps
+	if max := float64(l.burst); l.tokens > max {
+		l.tokens = max
+	}
+
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0, true
+	}
+
+	missing := 1 - l.tokens
+	return time.Duration(missing / l.qps * float64(time.Second)), false
+}
@@ -0,0 +1,68 @@
+package taxis99
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketLimiterThrottlesConcurrentRequests(t *testing.T) {
+	const n = 5
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	c := NewClient(nil)
+	u, _ := url.Parse(srv.URL + "/")
+	c.BaseURL = u
+	c.RateLimiter = NewTokenBucketLimiter(2, 1)
+
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.Request(context.Background(), http.MethodGet, "", nil, nil); err != nil {
+				t.Errorf("Got error calling Request: %s; want it to be nil.", err.Error())
+			}
+		}()
+	}
+	wg.Wait()
+
+	if elapsed, want := time.Since(start), time.Duration(n-1)/2*time.Second; elapsed < want {
+		t.Errorf("Got elapsed %s; want at least %s for %d requests at qps=2, burst=1.", elapsed, want, n)
+	}
+}
+
+func TestTokenBucketLimiterWaitCanceled(t *testing.T) {
+	l := NewTokenBucketLimiter(1, 1)
+
+	// Drain the single burst token.
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("Got error calling Wait: %s; want it to be nil.", err.Error())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := l.Wait(ctx); err == nil {
+		t.Error("Got error nil; want it not to be nil for a canceled context.")
+	}
+}
+
+func TestNoopLimiterNeverBlocks(t *testing.T) {
+	var l NoopLimiter
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := l.Wait(ctx); err != nil {
+		t.Fatalf("Got error calling Wait: %s; want it to be nil.", err.Error())
+	}
+}
@@ -0,0 +1,105 @@
+package taxis99
+
+import (
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how Client.Request retries a request that fails with
+// a retryable status code or a transport error. The zero value disables
+// retries: Request makes a single attempt.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first. A
+	// value less than 1 is treated as 1 (no retries).
+	MaxAttempts int
+
+	// InitialBackoff, MaxBackoff and Multiplier control the exponential
+	// backoff between attempts: min(MaxBackoff, InitialBackoff *
+	// Multiplier^attempt).
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+
+	// Jitter adds up to Jitter*backoff of additional random delay, as a
+	// fraction between 0 and 1.
+	Jitter float64
+
+	// RetryableStatus lists the HTTP status codes that trigger a retry. If
+	// nil, DefaultRetryableStatus is used.
+	RetryableStatus []int
+}
+
+// DefaultRetryableStatus is used by RetryPolicy when RetryableStatus is nil.
+var DefaultRetryableStatus = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+func (p RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+func (p RetryPolicy) retryableStatus(code int) bool {
+	statuses := p.RetryableStatus
+	if statuses == nil {
+		statuses = DefaultRetryableStatus
+	}
+
+	for _, s := range statuses {
+		if s == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the delay to wait before the given (zero-indexed) retry
+// attempt, including jitter.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(attempt))
+	if max := float64(p.MaxBackoff); max > 0 && d > max {
+		d = max
+	}
+
+	if p.Jitter > 0 {
+		d += d * p.Jitter * rand.Float64()
+	}
+
+	return time.Duration(d)
+}
+
+// retryAfter parses the Retry-After header (seconds or an HTTP-date) on a
+// 429 or 503 response, returning 0 if absent or unparseable.
+func retryAfter(res *http.Response) time.Duration {
+	if res.StatusCode != http.StatusTooManyRequests && res.StatusCode != http.StatusServiceUnavailable {
+		return 0
+	}
+
+	v := res.Header.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,244 @@
+package taxis99
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+type countingRoundTripper struct {
+	responses []*http.Response
+	errs      []error
+
+	calls     int
+	callTimes []time.Time
+}
+
+func (rt *countingRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	rt.callTimes = append(rt.callTimes, time.Now())
+
+	i := rt.calls
+	rt.calls++
+
+	if r.Body != nil {
+		r.Body.Close()
+	}
+
+	var err error
+	if i < len(rt.errs) {
+		err = rt.errs[i]
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if i < len(rt.responses) {
+		return rt.responses[i], nil
+	}
+	return rt.responses[len(rt.responses)-1], nil
+}
+
+func newStatusResponse(code int, header http.Header) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		StatusCode: code,
+		Header:     header,
+		Body:       http.NoBody,
+	}
+}
+
+func TestClientRequestRetryAttemptCount(t *testing.T) {
+	testCases := []struct {
+		name        string
+		statuses    []int
+		maxAttempts int
+		wantCalls   int
+		wantErr     bool
+	}{
+		{
+			name:        "succeeds after two retryable responses",
+			statuses:    []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			maxAttempts: 4,
+			wantCalls:   3,
+		},
+		{
+			name:        "gives up after MaxAttempts",
+			statuses:    []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable},
+			maxAttempts: 2,
+			wantCalls:   2,
+			wantErr:     true, // the last retryable response is still a non-2xx status
+		},
+		{
+			name:        "non-retryable status is not retried",
+			statuses:    []int{http.StatusBadRequest},
+			maxAttempts: 4,
+			wantCalls:   1,
+			wantErr:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			responses := make([]*http.Response, len(tc.statuses))
+			for i, code := range tc.statuses {
+				responses[i] = newStatusResponse(code, nil)
+			}
+
+			rt := &countingRoundTripper{responses: responses}
+			c := NewClient(&http.Client{Transport: rt})
+			c.Retry = RetryPolicy{MaxAttempts: tc.maxAttempts, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond, Multiplier: 2}
+
+			err := c.Request(context.Background(), http.MethodGet, "", nil, nil)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Got error %v; want error: %v", err, tc.wantErr)
+			}
+
+			if rt.calls != tc.wantCalls {
+				t.Errorf("Got %d calls; want %d.", rt.calls, tc.wantCalls)
+			}
+		})
+	}
+}
+
+func TestClientRequestRetryBackoffOrdering(t *testing.T) {
+	responses := []*http.Response{
+		newStatusResponse(http.StatusServiceUnavailable, nil),
+		newStatusResponse(http.StatusServiceUnavailable, nil),
+		newStatusResponse(http.StatusOK, nil),
+	}
+
+	rt := &countingRoundTripper{responses: responses}
+	c := NewClient(&http.Client{Transport: rt})
+	c.Retry = RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 20 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+	}
+
+	if err := c.Request(context.Background(), http.MethodGet, "", nil, nil); err != nil {
+		t.Fatalf("Got error calling Request: %s; want it to be nil.", err.Error())
+	}
+
+	if len(rt.callTimes) != 3 {
+		t.Fatalf("Got %d calls; want 3.", len(rt.callTimes))
+	}
+
+	firstGap := rt.callTimes[1].Sub(rt.callTimes[0])
+	secondGap := rt.callTimes[2].Sub(rt.callTimes[1])
+
+	if firstGap < 20*time.Millisecond {
+		t.Errorf("Got first backoff %s; want at least %s.", firstGap, 20*time.Millisecond)
+	}
+
+	if secondGap <= firstGap {
+		t.Errorf("Got second backoff %s; want it greater than first backoff %s.", secondGap, firstGap)
+	}
+}
+
+func TestClientRequestRetryAfterHeader(t *testing.T) {
+	header := http.Header{}
+	header.Set("Retry-After", "1")
+
+	responses := []*http.Response{
+		newStatusResponse(http.StatusTooManyRequests, header),
+		newStatusResponse(http.StatusOK, nil),
+	}
+
+	rt := &countingRoundTripper{responses: responses}
+	c := NewClient(&http.Client{Transport: rt})
+	c.Retry = RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, Multiplier: 1}
+
+	start := time.Now()
+	if err := c.Request(context.Background(), http.MethodGet, "", nil, nil); err != nil {
+		t.Fatalf("Got error calling Request: %s; want it to be nil.", err.Error())
+	}
+	elapsed := time.Since(start)
+
+	if elapsed < time.Second {
+		t.Errorf("Got elapsed %s; want at least 1s honoring Retry-After.", elapsed)
+	}
+}
+
+func TestClientRequestRetryContextCanceledBetweenAttempts(t *testing.T) {
+	responses := []*http.Response{
+		newStatusResponse(http.StatusServiceUnavailable, nil),
+		newStatusResponse(http.StatusOK, nil),
+	}
+
+	rt := &countingRoundTripper{responses: responses}
+	c := NewClient(&http.Client{Transport: rt})
+	c.Retry = RetryPolicy{MaxAttempts: 4, InitialBackoff: time.Hour, MaxBackoff: time.Hour, Multiplier: 1}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := c.Request(ctx, http.MethodGet, "", nil, nil)
+	if err == nil {
+		t.Error("Got error nil; want it not to be nil.")
+	}
+
+	if rt.calls != 1 {
+		t.Errorf("Got %d calls; want 1 (canceled before the retry could fire).", rt.calls)
+	}
+}
+
+func TestClientRequestRetryTransportError(t *testing.T) {
+	rt := &countingRoundTripper{
+		errs:      []error{errTest, errTest},
+		responses: []*http.Response{newStatusResponse(http.StatusOK, nil)},
+	}
+	c := NewClient(&http.Client{Transport: rt})
+	c.Retry = RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, Multiplier: 1}
+
+	if err := c.Request(context.Background(), http.MethodGet, "", nil, nil); err != nil {
+		t.Fatalf("Got error calling Request: %s; want it to be nil.", err.Error())
+	}
+
+	if rt.calls != 3 {
+		t.Errorf("Got %d calls; want 3.", rt.calls)
+	}
+}
+
+func TestClientRequestRetryReusesRequestBody(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := len(bodies)
+		b := make([]byte, r.ContentLength)
+		r.Body.Read(b)
+		bodies = append(bodies, string(b))
+
+		if n == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(nil)
+	u, _ := url.Parse(srv.URL + "/")
+	c.BaseURL = u
+	c.Retry = RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, Multiplier: 1}
+
+	payload := struct {
+		Name string `json:"name"`
+	}{"Test"}
+
+	if err := c.Request(context.Background(), http.MethodPost, "", payload, nil); err != nil {
+		t.Fatalf("Got error calling Request: %s; want it to be nil.", err.Error())
+	}
+
+	if len(bodies) != 2 {
+		t.Fatalf("Got %d requests; want 2.", len(bodies))
+	}
+
+	if bodies[0] != bodies[1] {
+		t.Errorf("Got bodies %q and %q; want the retried request body to match the original.", bodies[0], bodies[1])
+	}
+}
+
+var errTest = &url.Error{Op: "Get", URL: "http://example.com", Err: context.DeadlineExceeded}